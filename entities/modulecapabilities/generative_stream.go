@@ -0,0 +1,44 @@
+//                           _       _
+// __      _____  __ ___   ___  __ _| |_ ___
+// \ \ /\ / / _ \/ _` \ \ / / |/ _` | __/ _ \
+//  \ V  V /  __/ (_| |\ V /| | (_| | ||  __/
+//   \_/\_/ \___|\__,_| \_/ |_|\__,_|\__\___|
+//
+//  Copyright © 2016 - 2024 Weaviate B.V. All rights reserved.
+//
+//  CONTACT: hello@weaviate.io
+//
+
+package modulecapabilities
+
+import (
+	"context"
+
+	"github.com/weaviate/weaviate/entities/moduletools"
+)
+
+// GenerativeStreamChunk is a single incremental piece of a streamed
+// generative response. Finish is true exactly once, on the final chunk,
+// at which point Usage is populated if the provider reported it.
+type GenerativeStreamChunk struct {
+	Content string
+	Finish  bool
+	Usage   *GenerativeUsage
+}
+
+// GenerativeUsage is the token accounting reported by a generative
+// provider, typically in the final chunk/event of a streamed response.
+type GenerativeUsage struct {
+	PromptTokens     int
+	CompletionTokens int
+	TotalTokens      int
+}
+
+// GenerativeClientStream is implemented by generative modules that can
+// stream their response incrementally rather than only returning it once
+// complete. It is independent of GenerativeClient: a module may implement
+// either, both, or neither, and callers should check for this interface
+// before assuming streaming is available.
+type GenerativeClientStream interface {
+	GenerateStream(ctx context.Context, cfg moduletools.ClassConfig, prompt string, options interface{}, onChunk func(GenerativeStreamChunk) error) error
+}
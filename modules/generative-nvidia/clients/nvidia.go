@@ -0,0 +1,308 @@
+//                           _       _
+// __      _____  __ ___   ___  __ _| |_ ___
+// \ \ /\ / / _ \/ _` \ \ / / |/ _` | __/ _ \
+//  \ V  V /  __/ (_| |\ V /| | (_| | ||  __/
+//   \_/\_/ \___|\__,_| \_/ |_|\__,_|\__\___|
+//
+//  Copyright © 2016 - 2024 Weaviate B.V. All rights reserved.
+//
+//  CONTACT: hello@weaviate.io
+//
+
+package clients
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+
+	"github.com/weaviate/weaviate/entities/modulecapabilities"
+	"github.com/weaviate/weaviate/entities/moduletools"
+)
+
+const defaultBaseURL = "https://integrate.api.nvidia.com/v1"
+
+// nvidia talks to NVIDIA's NIM endpoints, which expose an
+// OpenAI-compatible `/v1/chat/completions` API supporting both a single
+// JSON response and `stream=true` SSE token chunks.
+type nvidia struct {
+	apiKey     string
+	httpClient *http.Client
+	logger     logrus.FieldLogger
+}
+
+// New returns a client for NVIDIA's NIM generative endpoints, authenticating
+// every request with apiKey.
+func New(apiKey string, timeout time.Duration, logger logrus.FieldLogger) *nvidia {
+	return &nvidia{
+		apiKey:     apiKey,
+		httpClient: &http.Client{Timeout: timeout},
+		logger:     logger,
+	}
+}
+
+type chatMessage struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+type chatCompletionsRequest struct {
+	Model    string        `json:"model"`
+	Messages []chatMessage `json:"messages"`
+	Stream   bool          `json:"stream"`
+}
+
+type usage struct {
+	PromptTokens     int `json:"prompt_tokens"`
+	CompletionTokens int `json:"completion_tokens"`
+	TotalTokens      int `json:"total_tokens"`
+}
+
+type chatCompletionsResponse struct {
+	Choices []struct {
+		Message chatMessage `json:"message"`
+	} `json:"choices"`
+	Usage *usage `json:"usage"`
+}
+
+// chatCompletionsChunk mirrors a single `data: {...}` SSE event emitted
+// when `stream=true`.
+type chatCompletionsChunk struct {
+	Choices []struct {
+		Delta struct {
+			Content string `json:"content"`
+		} `json:"delta"`
+		FinishReason *string `json:"finish_reason"`
+	} `json:"choices"`
+	Usage *usage `json:"usage"`
+}
+
+// GenerateSingleResult implements modulecapabilities.GenerativeClient for
+// a single object/prompt combination. The prompt is sent as-is: properties
+// are not substituted into it or otherwise included in the request.
+func (c *nvidia) GenerateSingleResult(ctx context.Context, properties map[string]string,
+	prompt string, options interface{}, debug bool, cfg moduletools.ClassConfig,
+) (*modulecapabilities.GenerateResponse, error) {
+	model := modelFromConfig(cfg)
+
+	resp, err := c.doChatCompletions(ctx, model, prompt)
+	if err != nil {
+		return nil, err
+	}
+
+	return toGenerateResponse(resp), nil
+}
+
+// GenerateAllResults implements modulecapabilities.GenerativeClient for a
+// task over a group of objects. The task is sent as-is: properties are not
+// included in the request.
+func (c *nvidia) GenerateAllResults(ctx context.Context, properties []map[string]string,
+	task string, options interface{}, debug bool, cfg moduletools.ClassConfig,
+) (*modulecapabilities.GenerateResponse, error) {
+	model := modelFromConfig(cfg)
+
+	resp, err := c.doChatCompletions(ctx, model, task)
+	if err != nil {
+		return nil, err
+	}
+
+	return toGenerateResponse(resp), nil
+}
+
+// GenerateStream implements modulecapabilities.GenerativeClientStream,
+// invoking onChunk for every incremental token NVIDIA's NIM endpoint
+// emits, and once more with Finish=true (and Usage, if reported) when the
+// stream ends.
+func (c *nvidia) GenerateStream(ctx context.Context, cfg moduletools.ClassConfig,
+	prompt string, options interface{}, onChunk func(modulecapabilities.GenerativeStreamChunk) error,
+) error {
+	model := modelFromConfig(cfg)
+
+	body, err := json.Marshal(chatCompletionsRequest{
+		Model:    model,
+		Messages: []chatMessage{{Role: "user", Content: prompt}},
+		Stream:   true,
+	})
+	if err != nil {
+		return errors.Wrap(err, "marshal request body")
+	}
+
+	req, err := c.newRequest(ctx, body)
+	if err != nil {
+		return err
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		return errors.Wrap(err, "send generate stream request")
+	}
+	// Abort the body read the moment ctx is cancelled, rather than waiting
+	// for the next Scan to notice.
+	go func() {
+		<-ctx.Done()
+		resp.Body.Close()
+	}()
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return parseErrorResponse(resp)
+	}
+
+	// lastUsage carries usage accounting forward from whichever SSE event
+	// reported it (often a trailing event with empty choices) to the single
+	// Finish:true chunk we emit once the stream ends, since `[DONE]` itself
+	// never carries a payload.
+	var lastUsage *modulecapabilities.GenerativeUsage
+
+	scanner := bufio.NewScanner(resp.Body)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || !strings.HasPrefix(line, "data:") {
+			continue
+		}
+		data := strings.TrimSpace(strings.TrimPrefix(line, "data:"))
+		if data == "[DONE]" {
+			return onChunk(modulecapabilities.GenerativeStreamChunk{Finish: true, Usage: lastUsage})
+		}
+
+		var chunk chatCompletionsChunk
+		if err := json.Unmarshal([]byte(data), &chunk); err != nil {
+			c.logger.WithError(err).WithField("data", data).Warn("nvidia: failed to unmarshal stream chunk")
+			return errors.Wrap(err, "unmarshal stream chunk")
+		}
+
+		out := modulecapabilities.GenerativeStreamChunk{}
+		if len(chunk.Choices) > 0 {
+			out.Content = chunk.Choices[0].Delta.Content
+		}
+		if chunk.Usage != nil {
+			lastUsage = &modulecapabilities.GenerativeUsage{
+				PromptTokens:     chunk.Usage.PromptTokens,
+				CompletionTokens: chunk.Usage.CompletionTokens,
+				TotalTokens:      chunk.Usage.TotalTokens,
+			}
+		}
+
+		if err := onChunk(out); err != nil {
+			return err
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		return errors.Wrap(err, "read stream body")
+	}
+
+	// The connection ended without a `[DONE]` sentinel (e.g. a truncating
+	// proxy or an upstream that just closes the body). Callers still need
+	// exactly one Finish:true chunk, so synthesize it here rather than
+	// silently returning without one.
+	return onChunk(modulecapabilities.GenerativeStreamChunk{Finish: true, Usage: lastUsage})
+}
+
+func (c *nvidia) doChatCompletions(ctx context.Context, model, prompt string) (*chatCompletionsResponse, error) {
+	body, err := json.Marshal(chatCompletionsRequest{
+		Model:    model,
+		Messages: []chatMessage{{Role: "user", Content: prompt}},
+		Stream:   false,
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "marshal request body")
+	}
+
+	req, err := c.newRequest(ctx, body)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, errors.Wrap(err, "send generate request")
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, parseErrorResponse(resp)
+	}
+
+	var out chatCompletionsResponse
+	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+		return nil, errors.Wrap(err, "unmarshal response body")
+	}
+
+	return &out, nil
+}
+
+func (c *nvidia) newRequest(ctx context.Context, body []byte) (*http.Request, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, defaultBaseURL+"/chat/completions", bytes.NewReader(body))
+	if err != nil {
+		return nil, errors.Wrap(err, "build request")
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if c.apiKey != "" {
+		req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", c.apiKey))
+	}
+	return req, nil
+}
+
+func parseErrorResponse(resp *http.Response) error {
+	buf, _ := io.ReadAll(resp.Body)
+	return errors.Errorf("nvidia NIM API returned status %d: %s", resp.StatusCode, string(buf))
+}
+
+// toGenerateResponse reconstructs the aggregated result modulecapabilities
+// expects from a non-streaming chat completion response.
+func toGenerateResponse(resp *chatCompletionsResponse) *modulecapabilities.GenerateResponse {
+	var result string
+	if len(resp.Choices) > 0 {
+		result = resp.Choices[0].Message.Content
+	}
+
+	out := &modulecapabilities.GenerateResponse{Result: &result}
+	if resp.Usage != nil {
+		out.Usage = &modulecapabilities.GenerativeUsage{
+			PromptTokens:     resp.Usage.PromptTokens,
+			CompletionTokens: resp.Usage.CompletionTokens,
+			TotalTokens:      resp.Usage.TotalTokens,
+		}
+	}
+	return out
+}
+
+// modelFromConfig reads the configured NIM model name off the class
+// config, falling back to a sane default so a class created before this
+// setting existed keeps working.
+func modelFromConfig(cfg moduletools.ClassConfig) string {
+	if cfg == nil {
+		return "meta/llama3-70b-instruct"
+	}
+	if model, ok := cfg.Class()["model"]; ok {
+		if s, ok := model.(string); ok && s != "" {
+			return s
+		}
+	}
+	return "meta/llama3-70b-instruct"
+}
+
+// MetaInfo returns metadata about this client for the module's MetaInfo
+// capability.
+func (c *nvidia) MetaInfo() (map[string]interface{}, error) {
+	return map[string]interface{}{
+		"name":    "Generative Search - NVIDIA",
+		"baseURL": defaultBaseURL,
+	}, nil
+}
@@ -0,0 +1,122 @@
+//                           _       _
+// __      _____  __ ___   ___  __ _| |_ ___
+// \ \ /\ / / _ \/ _` \ \ / / |/ _` | __/ _ \
+//  \ V  V /  __/ (_| |\ V /| | (_| | ||  __/
+//   \_/\_/ \___|\__,_| \_/ |_|\__,_|\__\___|
+//
+//  Copyright © 2016 - 2024 Weaviate B.V. All rights reserved.
+//
+//  CONTACT: hello@weaviate.io
+//
+
+package clients
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/weaviate/weaviate/entities/modulecapabilities"
+)
+
+// rewriteHostTransport redirects every request to addr instead of wherever
+// it was originally addressed, so tests can point GenerateStream's
+// hardcoded defaultBaseURL at an httptest server.
+type rewriteHostTransport struct {
+	addr string
+}
+
+func (t rewriteHostTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req.URL.Scheme = "http"
+	req.URL.Host = t.addr
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+// runStream spins up an httptest server emitting events as `data:` SSE
+// lines and runs GenerateStream against it, returning every chunk observed.
+func runStream(t *testing.T, events []string) []modulecapabilities.GenerativeStreamChunk {
+	t.Helper()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.WriteHeader(http.StatusOK)
+		bw := bufio.NewWriter(w)
+		for _, e := range events {
+			fmt.Fprintf(bw, "data: %s\n\n", e)
+		}
+		bw.Flush()
+	}))
+	defer srv.Close()
+
+	c := &nvidia{
+		apiKey:     "test-key",
+		httpClient: &http.Client{Transport: rewriteHostTransport{addr: srv.Listener.Addr().String()}},
+		logger:     logrus.New(),
+	}
+
+	var chunks []modulecapabilities.GenerativeStreamChunk
+	err := c.GenerateStream(context.Background(), nil, "say hi", nil,
+		func(chunk modulecapabilities.GenerativeStreamChunk) error {
+			chunks = append(chunks, chunk)
+			return nil
+		})
+	require.NoError(t, err)
+	return chunks
+}
+
+func TestNvidiaGenerateStream(t *testing.T) {
+	t.Run("Finish is true exactly once, with usage carried from the last event", func(t *testing.T) {
+		chunks := runStream(t, []string{
+			`{"choices":[{"delta":{"content":"hello"}}]}`,
+			`{"choices":[{"delta":{"content":" world"},"finish_reason":"stop"}]}`,
+			`{"choices":[],"usage":{"prompt_tokens":3,"completion_tokens":2,"total_tokens":5}}`,
+			`[DONE]`,
+		})
+
+		finishCount := 0
+		for _, c := range chunks {
+			if c.Finish {
+				finishCount++
+			}
+		}
+		assert.Equal(t, 1, finishCount, "Finish must be true exactly once")
+
+		last := chunks[len(chunks)-1]
+		assert.True(t, last.Finish)
+		require.NotNil(t, last.Usage)
+		assert.Equal(t, 3, last.Usage.PromptTokens)
+		assert.Equal(t, 2, last.Usage.CompletionTokens)
+		assert.Equal(t, 5, last.Usage.TotalTokens)
+
+		assert.Equal(t, "hello", chunks[0].Content)
+		assert.Equal(t, " world", chunks[1].Content)
+	})
+
+	t.Run("a connection that closes without [DONE] still gets exactly one Finish chunk", func(t *testing.T) {
+		chunks := runStream(t, []string{
+			`{"choices":[{"delta":{"content":"hello"}}]}`,
+			`{"choices":[],"usage":{"prompt_tokens":1,"completion_tokens":1,"total_tokens":2}}`,
+			// no [DONE]: server just closes the connection here.
+		})
+
+		finishCount := 0
+		for _, c := range chunks {
+			if c.Finish {
+				finishCount++
+			}
+		}
+		require.Equal(t, 1, finishCount, "Finish must be true exactly once even without a [DONE] sentinel")
+
+		last := chunks[len(chunks)-1]
+		assert.True(t, last.Finish)
+		require.NotNil(t, last.Usage)
+		assert.Equal(t, 2, last.Usage.TotalTokens)
+	})
+}
@@ -37,6 +37,7 @@ type GenerativeNvidiaModule struct {
 
 type generativeClient interface {
 	modulecapabilities.GenerativeClient
+	modulecapabilities.GenerativeClientStream
 	MetaInfo() (map[string]interface{}, error)
 }
 
@@ -74,6 +75,15 @@ func (m *GenerativeNvidiaModule) MetaInfo() (map[string]interface{}, error) {
 	return m.generative.MetaInfo()
 }
 
+// GenerateStream streams an incremental generative response, delegating to
+// the underlying NIM client. onChunk is invoked once per SSE token chunk
+// and once more, with Finish set, when the stream ends.
+func (m *GenerativeNvidiaModule) GenerateStream(ctx context.Context, cfg moduletools.ClassConfig,
+	prompt string, options interface{}, onChunk func(modulecapabilities.GenerativeStreamChunk) error,
+) error {
+	return m.generative.GenerateStream(ctx, cfg, prompt, options, onChunk)
+}
+
 func (m *GenerativeNvidiaModule) AdditionalGenerativeProperties() map[string]modulecapabilities.GenerativeProperty {
 	return m.additionalPropertiesProvider
 }
@@ -83,4 +93,5 @@ var (
 	_ = modulecapabilities.Module(New())
 	_ = modulecapabilities.MetaProvider(New())
 	_ = modulecapabilities.AdditionalGenerativeProperties(New())
+	_ = modulecapabilities.GenerativeClientStream(New())
 )
@@ -0,0 +1,44 @@
+//                           _       _
+// __      _____  __ ___   ___  __ _| |_ ___
+// \ \ /\ / / _ \/ _` \ \ / / |/ _` | __/ _ \
+//  \ V  V /  __/ (_| |\ V /| | (_| | ||  __/
+//   \_/\_/ \___|\__,_| \_/ |_|\__,_|\__\___|
+//
+//  Copyright © 2016 - 2024 Weaviate B.V. All rights reserved.
+//
+//  CONTACT: hello@weaviate.io
+//
+
+package runtime
+
+// dispatchWorkers bounds how many subscriber callbacks can run
+// concurrently across every DynamicValue in the process.
+const dispatchWorkers = 8
+
+// dispatchQueue decouples SetValue from subscriber callbacks: whatever
+// goroutine calls SetValue (often one holding a config-wide lock, e.g.
+// UpdateRuntimeConfig) only ever enqueues work here, it never runs a
+// subscriber's callback itself.
+var dispatchQueue = make(chan func(), 4096)
+
+func init() {
+	for i := 0; i < dispatchWorkers; i++ {
+		go dispatchLoop()
+	}
+}
+
+func dispatchLoop() {
+	for fn := range dispatchQueue {
+		fn()
+	}
+}
+
+// dispatch schedules fn to run on the worker pool. The guarantee that
+// SetValue never runs a subscriber's callback itself is absolute, so unlike
+// a typical bounded queue, dispatch blocks the caller until a slot frees up
+// rather than ever falling back to running fn inline: a blocked setter is
+// safe (it still isn't executing subscriber code), a setter that silently
+// starts running subscriber code under its own lock is not.
+func dispatch(fn func()) {
+	dispatchQueue <- fn
+}
@@ -0,0 +1,215 @@
+//                           _       _
+// __      _____  __ ___   ___  __ _| |_ ___
+// \ \ /\ / / _ \/ _` \ \ / / |/ _` | __/ _ \
+//  \ V  V /  __/ (_| |\ V /| | (_| | ||  __/
+//   \_/\_/ \___|\__,_| \_/ |_|\__,_|\__\___|
+//
+//  Copyright © 2016 - 2024 Weaviate B.V. All rights reserved.
+//
+//  CONTACT: hello@weaviate.io
+//
+
+// Package runtime holds the primitives used to represent configuration
+// values that may change while the process is running, without requiring a
+// restart.
+package runtime
+
+import (
+	"reflect"
+	"sync/atomic"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Validator is run against a candidate value before it is allowed to
+// become the new value of a DynamicValue. Returning an error rejects the
+// update.
+type Validator[T any] func(v T) error
+
+// SubscriptionID identifies a callback registered via Subscribe, so it can
+// later be removed with Unsubscribe.
+type SubscriptionID uint64
+
+type subscriberEntry[T any] struct {
+	id SubscriptionID
+	fn func(old, new T)
+}
+
+// DynamicValue holds a value of type T that can be read and overwritten
+// concurrently from multiple goroutines. The zero value is ready to use:
+// Get returns the zero value of T until SetValue (or a successful YAML
+// unmarshal) stores something.
+type DynamicValue[T any] struct {
+	val       atomic.Pointer[T]
+	validator atomic.Pointer[Validator[T]]
+
+	nextSubID atomic.Uint64
+	subs      atomic.Pointer[[]subscriberEntry[T]]
+}
+
+// NewDynamicValue returns a DynamicValue initialized with v.
+func NewDynamicValue[T any](v T) *DynamicValue[T] {
+	d := &DynamicValue[T]{}
+	d.SetValue(v)
+	return d
+}
+
+// Get returns the current value, or the zero value of T if it has never
+// been set. Calling Get on a nil receiver is safe and returns the zero
+// value, so callers don't need to nil-check optional runtime overrides.
+func (d *DynamicValue[T]) Get() T {
+	var zero T
+	if d == nil {
+		return zero
+	}
+	if v := d.val.Load(); v != nil {
+		return *v
+	}
+	return zero
+}
+
+// SetValue atomically overwrites the stored value. SetValue on a nil
+// receiver is a no-op, mirroring Get's nil-safety so callers never need to
+// guard an optional *DynamicValue[T] before writing to it.
+//
+// If the new value differs from the previous one, every subscriber
+// registered via Subscribe is notified exactly once with the (old, new)
+// pair, dispatched through a bounded worker pool so SetValue never runs a
+// subscriber's callback itself -- important for callers like
+// UpdateRuntimeConfig that call SetValue while holding a config-wide lock.
+func (d *DynamicValue[T]) SetValue(v T) {
+	if d == nil {
+		return
+	}
+
+	oldPtr := d.val.Swap(&v)
+	var old T
+	if oldPtr != nil {
+		old = *oldPtr
+	}
+
+	if !reflect.DeepEqual(old, v) {
+		d.notify(old, v)
+	}
+}
+
+// Subscribe registers fn to be called, asynchronously and at most once per
+// effective change, whenever SetValue stores a value that differs from the
+// previous one. It returns a SubscriptionID to later pass to Unsubscribe.
+// Subscribe on a nil receiver is a no-op, mirroring SetValue's
+// nil-safety; the returned ID is 0, which Unsubscribe also treats as a
+// no-op.
+func (d *DynamicValue[T]) Subscribe(fn func(old, new T)) SubscriptionID {
+	if d == nil || fn == nil {
+		return 0
+	}
+
+	for {
+		oldPtr := d.subs.Load()
+		var oldSlice []subscriberEntry[T]
+		if oldPtr != nil {
+			oldSlice = *oldPtr
+		}
+
+		id := SubscriptionID(d.nextSubID.Add(1))
+		next := make([]subscriberEntry[T], len(oldSlice)+1)
+		copy(next, oldSlice)
+		next[len(oldSlice)] = subscriberEntry[T]{id: id, fn: fn}
+
+		if d.subs.CompareAndSwap(oldPtr, &next) {
+			return id
+		}
+	}
+}
+
+// Unsubscribe removes a callback previously registered with Subscribe.
+// Unsubscribe on a nil receiver, or with an id that's already been removed
+// (or was never valid), is a no-op.
+func (d *DynamicValue[T]) Unsubscribe(id SubscriptionID) {
+	if d == nil || id == 0 {
+		return
+	}
+
+	for {
+		oldPtr := d.subs.Load()
+		if oldPtr == nil {
+			return
+		}
+		oldSlice := *oldPtr
+
+		idx := -1
+		for i, e := range oldSlice {
+			if e.id == id {
+				idx = i
+				break
+			}
+		}
+		if idx < 0 {
+			return
+		}
+
+		next := make([]subscriberEntry[T], 0, len(oldSlice)-1)
+		next = append(next, oldSlice[:idx]...)
+		next = append(next, oldSlice[idx+1:]...)
+
+		if d.subs.CompareAndSwap(oldPtr, &next) {
+			return
+		}
+	}
+}
+
+// notify fans the (old, new) pair out to every current subscriber, each
+// dispatched independently through the bounded worker pool.
+func (d *DynamicValue[T]) notify(old, new T) {
+	subsPtr := d.subs.Load()
+	if subsPtr == nil {
+		return
+	}
+
+	for _, e := range *subsPtr {
+		fn := e.fn
+		dispatch(func() { fn(old, new) })
+	}
+}
+
+// SetValidator registers v as the validator run by Validate. Passing a nil
+// Validator clears any previously registered one. SetValidator on a nil
+// receiver is a no-op, consistent with SetValue.
+func (d *DynamicValue[T]) SetValidator(v Validator[T]) {
+	if d == nil {
+		return
+	}
+	if v == nil {
+		d.validator.Store(nil)
+		return
+	}
+	d.validator.Store(&v)
+}
+
+// Validate runs the registered validator (if any) against v without
+// storing it. Callers that need to apply several DynamicValues atomically
+// -- e.g. UpdateRuntimeConfig -- call Validate on every candidate first and
+// only call SetValue once all of them pass. A nil receiver or a value with
+// no registered validator always passes.
+func (d *DynamicValue[T]) Validate(v T) error {
+	if d == nil {
+		return nil
+	}
+	validator := d.validator.Load()
+	if validator == nil || *validator == nil {
+		return nil
+	}
+	return (*validator)(v)
+}
+
+// UnmarshalYAML allows DynamicValue to be decoded directly from a YAML
+// scalar/mapping/sequence node, so struct fields of type *DynamicValue[T]
+// can be used as-is in config structs parsed with gopkg.in/yaml.v3.
+func (d *DynamicValue[T]) UnmarshalYAML(node *yaml.Node) error {
+	var v T
+	if err := node.Decode(&v); err != nil {
+		return err
+	}
+	d.SetValue(v)
+	return nil
+}
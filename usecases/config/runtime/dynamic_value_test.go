@@ -0,0 +1,145 @@
+//                           _       _
+// __      _____  __ ___   ___  __ _| |_ ___
+// \ \ /\ / / _ \/ _` \ \ / / |/ _` | __/ _ \
+//  \ V  V /  __/ (_| |\ V /| | (_| | ||  __/
+//   \_/\_/ \___|\__,_| \_/ |_|\__,_|\__\___|
+//
+//  Copyright © 2016 - 2024 Weaviate B.V. All rights reserved.
+//
+//  CONTACT: hello@weaviate.io
+//
+
+package runtime
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDynamicValueSubscribe(t *testing.T) {
+	t.Run("subscriber is notified exactly once per effective change", func(t *testing.T) {
+		d := NewDynamicValue(1)
+
+		var mu sync.Mutex
+		var got [][2]int
+		d.Subscribe(func(old, new int) {
+			mu.Lock()
+			defer mu.Unlock()
+			got = append(got, [2]int{old, new})
+		})
+
+		d.SetValue(2)
+		d.SetValue(2) // no-op: value didn't change, shouldn't notify again
+		d.SetValue(3)
+
+		require.Eventually(t, func() bool {
+			mu.Lock()
+			defer mu.Unlock()
+			return len(got) == 2
+		}, time.Second, time.Millisecond)
+
+		mu.Lock()
+		defer mu.Unlock()
+		assert.Equal(t, [2]int{1, 2}, got[0])
+		assert.Equal(t, [2]int{2, 3}, got[1])
+	})
+
+	t.Run("unsubscribed callback stops receiving notifications", func(t *testing.T) {
+		d := NewDynamicValue(1)
+
+		var mu sync.Mutex
+		calls := 0
+		id := d.Subscribe(func(old, new int) {
+			mu.Lock()
+			defer mu.Unlock()
+			calls++
+		})
+
+		d.SetValue(2)
+		require.Eventually(t, func() bool {
+			mu.Lock()
+			defer mu.Unlock()
+			return calls == 1
+		}, time.Second, time.Millisecond)
+
+		d.Unsubscribe(id)
+		d.SetValue(3)
+
+		// give any errant dispatch a chance to land before asserting it didn't.
+		time.Sleep(50 * time.Millisecond)
+		mu.Lock()
+		defer mu.Unlock()
+		assert.Equal(t, 1, calls)
+	})
+
+	t.Run("Subscribe/Unsubscribe on nil receiver should not panic", func(t *testing.T) {
+		var d *DynamicValue[int]
+
+		var id SubscriptionID
+		require.NotPanics(t, func() {
+			id = d.Subscribe(func(old, new int) {})
+		})
+		assert.Equal(t, SubscriptionID(0), id)
+
+		require.NotPanics(t, func() {
+			d.Unsubscribe(id)
+		})
+	})
+
+	t.Run("Unsubscribe with an unknown id is a no-op", func(t *testing.T) {
+		d := NewDynamicValue(1)
+		require.NotPanics(t, func() {
+			d.Unsubscribe(SubscriptionID(999))
+		})
+	})
+}
+
+func TestDispatch(t *testing.T) {
+	t.Run("dispatch blocks rather than running fn inline when the queue is saturated", func(t *testing.T) {
+		hold := make(chan struct{})
+		var wg sync.WaitGroup
+
+		// Occupy every worker, and fill the queue behind them, so the next
+		// dispatch has nowhere to go but to wait.
+		wg.Add(dispatchWorkers)
+		for i := 0; i < dispatchWorkers; i++ {
+			dispatch(func() {
+				wg.Done()
+				<-hold
+			})
+		}
+		wg.Wait()
+		for i := 0; i < cap(dispatchQueue); i++ {
+			dispatch(func() { <-hold })
+		}
+
+		ran := make(chan struct{})
+		blockedReturn := make(chan struct{})
+		go func() {
+			dispatch(func() { close(ran) })
+			close(blockedReturn)
+		}()
+
+		// If dispatch fell back to running fn inline, both channels would
+		// already be closed by now.
+		select {
+		case <-ran:
+			t.Fatal("fn ran before a worker was free; dispatch must not run fn inline under backpressure")
+		case <-blockedReturn:
+			t.Fatal("dispatch returned before a worker was free; did it run fn inline?")
+		case <-time.After(50 * time.Millisecond):
+		}
+
+		close(hold)
+
+		select {
+		case <-ran:
+		case <-time.After(time.Second):
+			t.Fatal("fn was never dispatched after a worker freed up")
+		}
+	})
+}
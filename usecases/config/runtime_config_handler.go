@@ -0,0 +1,46 @@
+//                           _       _
+// __      _____  __ ___   ___  __ _| |_ ___
+// \ \ /\ / / _ \/ _` \ \ / / |/ _` | __/ _ \
+//  \ V  V /  __/ (_| |\ V /| | (_| | ||  __/
+//   \_/\_/ \___|\__,_| \_/ |_|\__,_|\__\___|
+//
+//  Copyright © 2016 - 2024 Weaviate B.V. All rights reserved.
+//
+//  CONTACT: hello@weaviate.io
+//
+
+package config
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+// RuntimeConfigAdminPath is the path this package's handler should be
+// mounted at by the REST API setup.
+const RuntimeConfigAdminPath = "/v1/admin/runtime-config"
+
+// runtimeConfigResponse is the JSON body served by NewRuntimeConfigHandler.
+type runtimeConfigResponse struct {
+	Fields []RuntimeConfigFieldStatus `json:"fields"`
+}
+
+// NewRuntimeConfigHandler returns a read-only http.Handler reporting, for
+// every field of WeaviateRuntimeConfig, its current effective value, its
+// default value, whether it's currently at that default, and the
+// source/timestamp of the last change, if any. It only accepts GET.
+func NewRuntimeConfigHandler(registry *RuntimeConfigRegistry) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		resp := runtimeConfigResponse{Fields: registry.Snapshot()}
+
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(resp); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+	})
+}
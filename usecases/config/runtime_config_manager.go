@@ -0,0 +1,238 @@
+//                           _       _
+// __      _____  __ ___   ___  __ _| |_ ___
+// \ \ /\ / / _ \/ _` \ \ / / |/ _` | __/ _ \
+//  \ V  V /  __/ (_| |\ V /| | (_| | ||  __/
+//   \_/\_/ \___|\__,_| \_/ |_|\__,_|\__\___|
+//
+//  Copyright © 2016 - 2024 Weaviate B.V. All rights reserved.
+//
+//  CONTACT: hello@weaviate.io
+//
+
+package config
+
+import (
+	"context"
+	"reflect"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// prioritizedSource pairs a RuntimeConfigSource with its declared priority.
+// Higher priority wins when the same field is set by more than one source.
+type prioritizedSource struct {
+	source   RuntimeConfigSource
+	priority int
+}
+
+// RuntimeConfigManager watches one or more RuntimeConfigSources, merges
+// their effective configuration by priority, and applies the result to a
+// live WeaviateRuntimeConfig via UpdateRuntimeConfig on every change.
+//
+// A source that starts failing (e.g. a ConfigMap edited into invalid YAML)
+// does not wipe out the merged config: the manager keeps serving that
+// source's last successfully parsed snapshot until it recovers.
+type RuntimeConfigManager struct {
+	log      logrus.FieldLogger
+	reg      *WeaviateRuntimeConfig
+	registry *RuntimeConfigRegistry
+	sources  []prioritizedSource
+
+	// RetryBackoff controls the delay before retrying a source whose Load
+	// or Watch attempt failed. Defaults to exponential backoff starting at
+	// one second, capped at one minute, if left unset.
+	RetryBackoff func(attempt int) time.Duration
+
+	mu        sync.Mutex
+	snapshots map[string]*WeaviateRuntimeConfig // last known-good parsed config, keyed by source Name()
+}
+
+// NewRuntimeConfigManager returns a manager that applies changes onto reg,
+// recording provenance (which source set each field, and when) into
+// registry so the admin runtime-config endpoint and Prometheus metrics can
+// report it. Sources are registered via AddSource before calling Run.
+func NewRuntimeConfigManager(log logrus.FieldLogger, reg *WeaviateRuntimeConfig, registry *RuntimeConfigRegistry) *RuntimeConfigManager {
+	return &RuntimeConfigManager{
+		log:       log,
+		reg:       reg,
+		registry:  registry,
+		snapshots: make(map[string]*WeaviateRuntimeConfig),
+	}
+}
+
+// AddSource registers source with the given priority. Sources with a
+// higher priority take precedence, field-by-field, over lower-priority
+// ones whenever both set the same field.
+func (m *RuntimeConfigManager) AddSource(source RuntimeConfigSource, priority int) {
+	m.sources = append(m.sources, prioritizedSource{source: source, priority: priority})
+}
+
+// Run loads every registered source, applies the merged result once, then
+// watches all sources for changes until ctx is cancelled. Run only returns
+// once every source's Watch has returned (normally because ctx was
+// cancelled).
+func (m *RuntimeConfigManager) Run(ctx context.Context) error {
+	for _, ps := range m.sources {
+		buf, err := m.loadWithRetry(ctx, ps.source)
+		if err != nil {
+			// A source that has never produced a usable config simply
+			// contributes nothing, rather than blocking startup.
+			m.log.WithError(err).WithField("source", ps.source.Name()).
+				Error("runtime overrides: initial load failed, starting without this source")
+			continue
+		}
+		m.ingest(ps.source.Name(), buf)
+	}
+	m.apply()
+
+	var wg sync.WaitGroup
+	for _, ps := range m.sources {
+		wg.Add(1)
+		go func(ps prioritizedSource) {
+			defer wg.Done()
+			m.watchWithRetry(ctx, ps.source)
+		}(ps)
+	}
+	wg.Wait()
+
+	return ctx.Err()
+}
+
+// loadWithRetry calls source.Load, retrying with backoff after each
+// failure. It returns as soon as a Load succeeds, returns ctx.Err() if ctx
+// is cancelled, and gives up with the last error after six failed attempts.
+func (m *RuntimeConfigManager) loadWithRetry(ctx context.Context, source RuntimeConfigSource) ([]byte, error) {
+	var lastErr error
+	for attempt := 0; ; attempt++ {
+		buf, err := source.Load(ctx)
+		if err == nil {
+			return buf, nil
+		}
+		lastErr = err
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(m.backoff(attempt)):
+		}
+
+		if attempt >= 5 {
+			return nil, lastErr
+		}
+	}
+}
+
+// watchWithRetry runs source.Watch, restarting it with backoff if it
+// returns a transient error, until ctx is cancelled.
+func (m *RuntimeConfigManager) watchWithRetry(ctx context.Context, source RuntimeConfigSource) {
+	for attempt := 0; ; attempt++ {
+		err := source.Watch(ctx, func(buf []byte) {
+			m.ingest(source.Name(), buf)
+			m.apply()
+		})
+		if ctx.Err() != nil {
+			return
+		}
+
+		m.log.WithError(err).WithField("source", source.Name()).
+			Warn("runtime overrides: watch failed, retrying")
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(m.backoff(attempt)):
+		}
+	}
+}
+
+func (m *RuntimeConfigManager) backoff(attempt int) time.Duration {
+	if m.RetryBackoff != nil {
+		return m.RetryBackoff(attempt)
+	}
+	d := time.Second << attempt
+	if d > time.Minute || d <= 0 {
+		d = time.Minute
+	}
+	return d
+}
+
+// ingest parses buf and, on success, stores it as the named source's
+// latest known-good snapshot. A parse failure leaves the previous snapshot
+// (if any) untouched, so a single bad edit to a ConfigMap or file can't
+// wipe out live overrides.
+func (m *RuntimeConfigManager) ingest(name string, buf []byte) {
+	parsed, err := ParseRuntimeConfig(buf)
+	if err != nil {
+		m.log.WithError(err).WithField("source", name).
+			Warn("runtime overrides: failed to parse update, keeping last known-good snapshot")
+		return
+	}
+
+	m.mu.Lock()
+	m.snapshots[name] = parsed
+	m.mu.Unlock()
+}
+
+// apply merges every source's last known-good snapshot by priority and
+// pushes the result onto the registry via UpdateRuntimeConfig, recording
+// per-field provenance and Prometheus metrics for every effective change.
+func (m *RuntimeConfigManager) apply() {
+	m.mu.Lock()
+	merged, fieldSources := m.merge()
+	m.mu.Unlock()
+
+	err := UpdateRuntimeConfig(m.log, m.reg, merged, WithChangeObserver(func(field string, old, new any) {
+		if m.registry != nil {
+			m.registry.recordChange(field, fieldSources[field])
+		}
+	}))
+	if err != nil {
+		m.log.WithError(err).Error("runtime overrides: failed to apply merged config")
+		return
+	}
+	if m.registry != nil {
+		RecordRuntimeConfigGauges(m.registry.Snapshot())
+	}
+}
+
+// merge combines all currently known snapshots into a single
+// WeaviateRuntimeConfig, preferring the highest-priority source that has a
+// non-nil value for each field, and returns which source's Name() won for
+// each field it set. Callers must hold m.mu.
+func (m *RuntimeConfigManager) merge() (*WeaviateRuntimeConfig, map[string]string) {
+	ordered := make([]prioritizedSource, len(m.sources))
+	copy(ordered, m.sources)
+	sort.Slice(ordered, func(i, j int) bool { return ordered[i].priority > ordered[j].priority })
+
+	merged := &WeaviateRuntimeConfig{}
+	mergedVal := reflect.ValueOf(merged).Elem()
+	mergedType := mergedVal.Type()
+	fieldSources := make(map[string]string)
+
+	for _, ps := range ordered {
+		snapshot, ok := m.snapshots[ps.source.Name()]
+		if !ok {
+			continue
+		}
+		snapshotVal := reflect.ValueOf(snapshot).Elem()
+
+		for i := 0; i < mergedType.NumField(); i++ {
+			dst := mergedVal.Field(i)
+			if !dst.IsNil() {
+				// Already set by a higher-priority source.
+				continue
+			}
+			src := snapshotVal.Field(i)
+			if src.IsNil() {
+				continue
+			}
+			dst.Set(src)
+			fieldSources[mergedType.Field(i).Name] = ps.source.Name()
+		}
+	}
+
+	return merged, fieldSources
+}
@@ -0,0 +1,102 @@
+//                           _       _
+// __      _____  __ ___   ___  __ _| |_ ___
+// \ \ /\ / / _ \/ _` \ \ / / |/ _` | __/ _ \
+//  \ V  V /  __/ (_| |\ V /| | (_| | ||  __/
+//   \_/\_/ \___|\__,_| \_/ |_|\__,_|\__\___|
+//
+//  Copyright © 2016 - 2024 Weaviate B.V. All rights reserved.
+//
+//  CONTACT: hello@weaviate.io
+//
+
+package config
+
+import (
+	"context"
+	"io"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/weaviate/weaviate/usecases/config/runtime"
+)
+
+func newTestManager() (*RuntimeConfigManager, *WeaviateRuntimeConfig) {
+	log := logrus.New()
+	log.SetOutput(io.Discard)
+
+	reg := &WeaviateRuntimeConfig{
+		MaximumAllowedCollectionsCount: &runtime.DynamicValue[int]{},
+		AutoschemaEnabled:              &runtime.DynamicValue[bool]{},
+	}
+	m := NewRuntimeConfigManager(log, reg, NewRuntimeConfigRegistry(reg))
+	return m, reg
+}
+
+func TestRuntimeConfigManagerMerge(t *testing.T) {
+	t.Run("a higher-priority source wins a field set by more than one source", func(t *testing.T) {
+		m, _ := newTestManager()
+		m.AddSource(nopSource{name: "low"}, 1)
+		m.AddSource(nopSource{name: "high"}, 10)
+
+		m.ingest("low", []byte(`autoschema_enabled: false
+maximum_allowed_collections_count: 1`))
+		m.ingest("high", []byte(`autoschema_enabled: true`))
+
+		merged, fieldSources := m.merge()
+		assert.Equal(t, true, merged.AutoschemaEnabled.Get())
+		assert.Equal(t, "high", fieldSources["AutoschemaEnabled"])
+
+		// "low" is the only source that set this field.
+		assert.Equal(t, 1, merged.MaximumAllowedCollectionsCount.Get())
+		assert.Equal(t, "low", fieldSources["MaximumAllowedCollectionsCount"])
+	})
+
+	t.Run("ingesting an unparsable update keeps the last known-good snapshot", func(t *testing.T) {
+		m, _ := newTestManager()
+		m.AddSource(nopSource{name: "file"}, 1)
+
+		m.ingest("file", []byte(`maximum_allowed_collections_count: 7`))
+		m.ingest("file", []byte(`not: valid: yaml:`))
+
+		merged, _ := m.merge()
+		assert.Equal(t, 7, merged.MaximumAllowedCollectionsCount.Get())
+	})
+}
+
+func TestRuntimeConfigManagerApply(t *testing.T) {
+	t.Run("apply pushes the merged config onto reg and records provenance", func(t *testing.T) {
+		m, reg := newTestManager()
+		m.AddSource(nopSource{name: "file"}, 1)
+
+		m.ingest("file", []byte(`autoschema_enabled: true`))
+		m.apply()
+
+		assert.Equal(t, true, reg.AutoschemaEnabled.Get())
+
+		var found *RuntimeConfigFieldStatus
+		for _, fc := range m.registry.Snapshot() {
+			if fc.Field == "AutoschemaEnabled" {
+				fc := fc
+				found = &fc
+			}
+		}
+		require.NotNil(t, found)
+		assert.Equal(t, "file", found.Source)
+	})
+}
+
+// nopSource is a RuntimeConfigSource whose Load/Watch are never exercised by
+// these tests -- manager tests drive ingest/merge/apply directly.
+type nopSource struct{ name string }
+
+func (s nopSource) Name() string { return s.name }
+
+func (s nopSource) Load(ctx context.Context) ([]byte, error) { return nil, nil }
+
+func (s nopSource) Watch(ctx context.Context, onChange func([]byte)) error {
+	<-ctx.Done()
+	return ctx.Err()
+}
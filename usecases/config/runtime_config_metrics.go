@@ -0,0 +1,92 @@
+//                           _       _
+// __      _____  __ ___   ___  __ _| |_ ___
+// \ \ /\ / / _ \/ _` \ \ / / |/ _` | __/ _ \
+//  \ V  V /  __/ (_| |\ V /| | (_| | ||  __/
+//   \_/\_/ \___|\__,_| \_/ |_|\__,_|\__\___|
+//
+//  Copyright © 2016 - 2024 Weaviate B.V. All rights reserved.
+//
+//  CONTACT: hello@weaviate.io
+//
+
+package config
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	runtimeConfigValue = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "weaviate_runtime_config_value",
+		Help: "Current effective value of a numeric/boolean/duration runtime config override. Booleans are reported as 0/1, durations in seconds.",
+	}, []string{"field"})
+
+	runtimeConfigIsDefault = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "weaviate_runtime_config_is_default",
+		Help: "1 if the runtime config field is currently at its default (zero) value, 0 if it has been overridden.",
+	}, []string{"field"})
+
+	runtimeConfigReloadsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "weaviate_runtime_config_reloads_total",
+		Help: "Total number of attempts to apply a new runtime config, labeled by result.",
+	}, []string{"result"})
+)
+
+// RegisterRuntimeConfigMetrics registers the runtime config Prometheus
+// collectors with reg. Call once at startup, e.g. alongside the rest of
+// the node's collectors.
+func RegisterRuntimeConfigMetrics(reg prometheus.Registerer) {
+	reg.MustRegister(runtimeConfigValue, runtimeConfigIsDefault, runtimeConfigReloadsTotal)
+}
+
+// recordRuntimeConfigReload increments the reloads counter for the outcome
+// of a single UpdateRuntimeConfig call.
+func recordRuntimeConfigReload(err error) {
+	result := "success"
+	if err != nil {
+		result = "error"
+	}
+	runtimeConfigReloadsTotal.WithLabelValues(result).Inc()
+}
+
+// RecordRuntimeConfigGauges refreshes the per-field gauges from snapshot.
+// Fields whose value can't be meaningfully reduced to a float64 (e.g.
+// strings like TenantActivityReadLogLevel) only update the is_default
+// gauge; weaviate_runtime_config_value is left untouched for them.
+func RecordRuntimeConfigGauges(snapshot []RuntimeConfigFieldStatus) {
+	for _, fc := range snapshot {
+		if v, ok := numericGaugeValue(fc.Value); ok {
+			runtimeConfigValue.WithLabelValues(fc.Field).Set(v)
+		}
+
+		isDefault := 0.0
+		if fc.IsDefault {
+			isDefault = 1.0
+		}
+		runtimeConfigIsDefault.WithLabelValues(fc.Field).Set(isDefault)
+	}
+}
+
+// numericGaugeValue converts the subset of runtime config value types that
+// map naturally onto a Prometheus gauge.
+func numericGaugeValue(v any) (float64, bool) {
+	switch t := v.(type) {
+	case int:
+		return float64(t), true
+	case int64:
+		return float64(t), true
+	case float64:
+		return t, true
+	case bool:
+		if t {
+			return 1, true
+		}
+		return 0, true
+	case time.Duration:
+		return t.Seconds(), true
+	default:
+		return 0, false
+	}
+}
@@ -0,0 +1,100 @@
+//                           _       _
+// __      _____  __ ___   ___  __ _| |_ ___
+// \ \ /\ / / _ \/ _` \ \ / / |/ _` | __/ _ \
+//  \ V  V /  __/ (_| |\ V /| | (_| | ||  __/
+//   \_/\_/ \___|\__,_| \_/ |_|\__,_|\__\___|
+//
+//  Copyright © 2016 - 2024 Weaviate B.V. All rights reserved.
+//
+//  CONTACT: hello@weaviate.io
+//
+
+package config
+
+import (
+	"reflect"
+	"sync"
+	"time"
+)
+
+// RuntimeConfigFieldStatus is the read-only view of one
+// WeaviateRuntimeConfig field exposed by the admin runtime-config endpoint
+// and used to populate Prometheus metrics.
+type RuntimeConfigFieldStatus struct {
+	Field     string    `json:"field"`
+	Type      string    `json:"type"`
+	Value     any       `json:"value"`
+	Default   any       `json:"default"`
+	IsDefault bool      `json:"is_default"`
+	Source    string    `json:"source,omitempty"`
+	ChangedAt time.Time `json:"changed_at,omitempty"`
+}
+
+// RuntimeConfigRegistry wraps a live WeaviateRuntimeConfig with the
+// provenance metadata (which RuntimeConfigSource last changed a field, and
+// when) that isn't otherwise recoverable from a DynamicValue alone.
+// RuntimeConfigManager records changes into it as they're applied; the
+// admin HTTP handler and Prometheus metrics both read Snapshot().
+type RuntimeConfigRegistry struct {
+	reg *WeaviateRuntimeConfig
+
+	mu         sync.RWMutex
+	provenance map[string]fieldProvenance
+}
+
+type fieldProvenance struct {
+	source    string
+	changedAt time.Time
+}
+
+// NewRuntimeConfigRegistry returns a registry reporting on reg.
+func NewRuntimeConfigRegistry(reg *WeaviateRuntimeConfig) *RuntimeConfigRegistry {
+	return &RuntimeConfigRegistry{
+		reg:        reg,
+		provenance: make(map[string]fieldProvenance),
+	}
+}
+
+// recordChange records that field's effective value was last changed by
+// source, at the current time.
+func (r *RuntimeConfigRegistry) recordChange(field, source string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.provenance[field] = fieldProvenance{source: source, changedAt: time.Now()}
+}
+
+// Snapshot returns the current status of every WeaviateRuntimeConfig field,
+// in declaration order.
+func (r *RuntimeConfigRegistry) Snapshot() []RuntimeConfigFieldStatus {
+	schema := DescribeRuntimeConfig()
+
+	regVal := reflect.ValueOf(r.reg).Elem()
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	out := make([]RuntimeConfigFieldStatus, 0, len(schema))
+	for _, fc := range schema {
+		target := regVal.FieldByName(fc.Field)
+		status := RuntimeConfigFieldStatus{
+			Field:     fc.Field,
+			Type:      fc.Type,
+			Default:   fc.Default,
+			Value:     fc.Default,
+			IsDefault: true,
+		}
+
+		if target.IsValid() && !target.IsNil() {
+			status.Value = target.MethodByName("Get").Call(nil)[0].Interface()
+			status.IsDefault = reflect.DeepEqual(status.Value, fc.Default)
+		}
+
+		if p, ok := r.provenance[fc.Field]; ok {
+			status.Source = p.source
+			status.ChangedAt = p.changedAt
+		}
+
+		out = append(out, status)
+	}
+	return out
+}
@@ -0,0 +1,112 @@
+//                           _       _
+// __      _____  __ ___   ___  __ _| |_ ___
+// \ \ /\ / / _ \/ _` \ \ / / |/ _` | __/ _ \
+//  \ V  V /  __/ (_| |\ V /| | (_| | ||  __/
+//   \_/\_/ \___|\__,_| \_/ |_|\__,_|\__\___|
+//
+//  Copyright © 2016 - 2024 Weaviate B.V. All rights reserved.
+//
+//  CONTACT: hello@weaviate.io
+//
+
+package config
+
+import (
+	"io"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/weaviate/weaviate/usecases/config/runtime"
+)
+
+func TestRuntimeConfigRegistrySnapshot(t *testing.T) {
+	log := logrus.New()
+	log.SetOutput(io.Discard)
+
+	t.Run("reflects the live value and provenance recorded for a changed field", func(t *testing.T) {
+		var colCount runtime.DynamicValue[int]
+		reg := &WeaviateRuntimeConfig{MaximumAllowedCollectionsCount: &colCount}
+		registry := NewRuntimeConfigRegistry(reg)
+
+		parsed, err := ParseRuntimeConfig([]byte(`maximum_allowed_collections_count: 42`))
+		require.NoError(t, err)
+
+		require.NoError(t, UpdateRuntimeConfig(log, reg, parsed, WithChangeObserver(func(field string, old, new any) {
+			registry.recordChange(field, "unit-test-source")
+		})))
+
+		snapshot := registry.Snapshot()
+		var found *RuntimeConfigFieldStatus
+		for i := range snapshot {
+			if snapshot[i].Field == "MaximumAllowedCollectionsCount" {
+				found = &snapshot[i]
+			}
+		}
+		require.NotNil(t, found)
+		assert.Equal(t, 42, found.Value)
+		assert.False(t, found.IsDefault)
+		assert.Equal(t, "unit-test-source", found.Source)
+		assert.False(t, found.ChangedAt.IsZero())
+	})
+
+	t.Run("an untouched field reports its default and no provenance", func(t *testing.T) {
+		var autoSchema runtime.DynamicValue[bool]
+		reg := &WeaviateRuntimeConfig{AutoschemaEnabled: &autoSchema}
+		registry := NewRuntimeConfigRegistry(reg)
+
+		snapshot := registry.Snapshot()
+		var found *RuntimeConfigFieldStatus
+		for i := range snapshot {
+			if snapshot[i].Field == "AutoschemaEnabled" {
+				found = &snapshot[i]
+			}
+		}
+		require.NotNil(t, found)
+		assert.True(t, found.IsDefault)
+		assert.Equal(t, "", found.Source)
+	})
+}
+
+func TestRecordRuntimeConfigGauges(t *testing.T) {
+	t.Run("sets the value and is_default gauges from a snapshot", func(t *testing.T) {
+		RecordRuntimeConfigGauges([]RuntimeConfigFieldStatus{
+			{Field: "MaximumAllowedCollectionsCount", Value: 13, Default: 0, IsDefault: false},
+		})
+
+		assert.Equal(t, float64(13), testutil.ToFloat64(runtimeConfigValue.WithLabelValues("MaximumAllowedCollectionsCount")))
+		assert.Equal(t, float64(0), testutil.ToFloat64(runtimeConfigIsDefault.WithLabelValues("MaximumAllowedCollectionsCount")))
+	})
+}
+
+func TestUpdateRuntimeConfigRecordsReloadMetric(t *testing.T) {
+	log := logrus.New()
+	log.SetOutput(io.Discard)
+
+	t.Run("counts a successful call under result=success", func(t *testing.T) {
+		before := testutil.ToFloat64(runtimeConfigReloadsTotal.WithLabelValues("success"))
+
+		var autoSchema runtime.DynamicValue[bool]
+		reg := &WeaviateRuntimeConfig{AutoschemaEnabled: &autoSchema}
+		parsed, err := ParseRuntimeConfig([]byte(`autoschema_enabled: true`))
+		require.NoError(t, err)
+
+		require.NoError(t, UpdateRuntimeConfig(log, reg, parsed))
+		assert.Equal(t, before+1, testutil.ToFloat64(runtimeConfigReloadsTotal.WithLabelValues("success")))
+	})
+
+	t.Run("counts a rejected call under result=error", func(t *testing.T) {
+		before := testutil.ToFloat64(runtimeConfigReloadsTotal.WithLabelValues("error"))
+
+		var colCount runtime.DynamicValue[int]
+		reg := &WeaviateRuntimeConfig{MaximumAllowedCollectionsCount: &colCount}
+		parsed, err := ParseRuntimeConfig([]byte(`maximum_allowed_collections_count: -1`))
+		require.NoError(t, err)
+
+		require.Error(t, UpdateRuntimeConfig(log, reg, parsed))
+		assert.Equal(t, before+1, testutil.ToFloat64(runtimeConfigReloadsTotal.WithLabelValues("error")))
+	})
+}
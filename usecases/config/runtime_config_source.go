@@ -0,0 +1,295 @@
+//                           _       _
+// __      _____  __ ___   ___  __ _| |_ ___
+// \ \ /\ / / _ \/ _` \ \ / / |/ _` | __/ _ \
+//  \ V  V /  __/ (_| |\ V /| | (_| | ||  __/
+//   \_/\_/ \___|\__,_| \_/ |_|\__,_|\__\___|
+//
+//  Copyright © 2016 - 2024 Weaviate B.V. All rights reserved.
+//
+//  CONTACT: hello@weaviate.io
+//
+
+package config
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/cache"
+)
+
+// RuntimeConfigSource produces the raw bytes of a runtime-overrides YAML
+// document (see ParseRuntimeConfig) and notifies callers whenever those
+// bytes change. Implementations must be safe to call Load and Watch from
+// separate goroutines, since RuntimeConfigManager does exactly that during
+// startup and reload.
+type RuntimeConfigSource interface {
+	// Name identifies the source for logging and error messages, e.g.
+	// "file:/etc/weaviate/runtime-overrides.yaml" or
+	// "configmap:weaviate/runtime-overrides".
+	Name() string
+
+	// Load returns the current raw contents of the source. It is called
+	// once at startup to obtain the initial configuration before Watch is
+	// started.
+	Load(ctx context.Context) ([]byte, error)
+
+	// Watch blocks, invoking onChange with the new contents every time the
+	// source changes, until ctx is cancelled (in which case Watch returns
+	// ctx.Err()) or an unrecoverable error occurs. Sources that only
+	// support polling should loop internally rather than requiring the
+	// caller to invoke Watch repeatedly.
+	Watch(ctx context.Context, onChange func([]byte)) error
+}
+
+// FileRuntimeConfigSource watches a single YAML file on the local
+// filesystem using fsnotify, re-reading it whenever it changes.
+type FileRuntimeConfigSource struct {
+	path string
+	log  logrus.FieldLogger
+}
+
+// NewFileRuntimeConfigSource returns a RuntimeConfigSource backed by the
+// local file at path.
+func NewFileRuntimeConfigSource(path string, log logrus.FieldLogger) *FileRuntimeConfigSource {
+	return &FileRuntimeConfigSource{path: path, log: log}
+}
+
+func (s *FileRuntimeConfigSource) Name() string {
+	return fmt.Sprintf("file:%s", s.path)
+}
+
+func (s *FileRuntimeConfigSource) Load(ctx context.Context) ([]byte, error) {
+	buf, err := os.ReadFile(s.path)
+	if err != nil {
+		return nil, errors.Wrapf(err, "read runtime config file %q", s.path)
+	}
+	return buf, nil
+}
+
+func (s *FileRuntimeConfigSource) Watch(ctx context.Context, onChange func([]byte)) error {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return errors.Wrap(err, "create fsnotify watcher")
+	}
+	defer watcher.Close()
+
+	// Watch the parent directory rather than the file itself: editors and
+	// `kubectl cp`/configmap volume remounts commonly replace the file via
+	// rename rather than an in-place write, which doesn't emit a usable
+	// event if we're only watching the (now stale) inode.
+	dir := filepath.Dir(s.path)
+	if err := watcher.Add(dir); err != nil {
+		return errors.Wrapf(err, "watch directory %q", dir)
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case event, ok := <-watcher.Events:
+			if !ok {
+				return errors.New("fsnotify watcher closed")
+			}
+			if filepath.Clean(event.Name) != filepath.Clean(s.path) {
+				continue
+			}
+			if event.Op&(fsnotify.Write|fsnotify.Create|fsnotify.Rename) == 0 {
+				continue
+			}
+			buf, err := s.Load(ctx)
+			if err != nil {
+				s.log.WithError(err).WithField("source", s.Name()).
+					Warn("runtime overrides: failed to reload file source, keeping last known-good snapshot")
+				continue
+			}
+			onChange(buf)
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return errors.New("fsnotify watcher closed")
+			}
+			s.log.WithError(err).WithField("source", s.Name()).Warn("runtime overrides: fsnotify error")
+		}
+	}
+}
+
+// ConfigMapRuntimeConfigSource watches a single key of a Kubernetes
+// ConfigMap using a client-go shared informer.
+type ConfigMapRuntimeConfigSource struct {
+	client    kubernetes.Interface
+	namespace string
+	name      string
+	key       string
+	log       logrus.FieldLogger
+}
+
+// NewConfigMapRuntimeConfigSource returns a RuntimeConfigSource backed by
+// the given key of the named ConfigMap.
+func NewConfigMapRuntimeConfigSource(client kubernetes.Interface, namespace, name, key string, log logrus.FieldLogger) *ConfigMapRuntimeConfigSource {
+	return &ConfigMapRuntimeConfigSource{client: client, namespace: namespace, name: name, key: key, log: log}
+}
+
+func (s *ConfigMapRuntimeConfigSource) Name() string {
+	return fmt.Sprintf("configmap:%s/%s", s.namespace, s.name)
+}
+
+func (s *ConfigMapRuntimeConfigSource) Load(ctx context.Context) ([]byte, error) {
+	cm, err := s.client.CoreV1().ConfigMaps(s.namespace).Get(ctx, s.name, metav1.GetOptions{})
+	if err != nil {
+		return nil, errors.Wrapf(err, "get configmap %s/%s", s.namespace, s.name)
+	}
+	return s.extract(cm)
+}
+
+func (s *ConfigMapRuntimeConfigSource) extract(cm *corev1.ConfigMap) ([]byte, error) {
+	data, ok := cm.Data[s.key]
+	if !ok {
+		return nil, errors.Errorf("configmap %s/%s has no key %q", s.namespace, s.name, s.key)
+	}
+	return []byte(data), nil
+}
+
+func (s *ConfigMapRuntimeConfigSource) Watch(ctx context.Context, onChange func([]byte)) error {
+	factory := informers.NewSharedInformerFactoryWithOptions(s.client, 0,
+		informers.WithNamespace(s.namespace),
+		informers.WithTweakListOptions(func(opts *metav1.ListOptions) {
+			opts.FieldSelector = fmt.Sprintf("metadata.name=%s", s.name)
+		}),
+	)
+	informer := factory.Core().V1().ConfigMaps().Informer()
+
+	handle := func(obj interface{}) {
+		cm, ok := obj.(*corev1.ConfigMap)
+		if !ok {
+			return
+		}
+		buf, err := s.extract(cm)
+		if err != nil {
+			s.log.WithError(err).WithField("source", s.Name()).
+				Warn("runtime overrides: failed to extract key from configmap update, keeping last known-good snapshot")
+			return
+		}
+		onChange(buf)
+	}
+
+	if _, err := informer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: handle,
+		UpdateFunc: func(_, newObj interface{}) {
+			handle(newObj)
+		},
+	}); err != nil {
+		return errors.Wrap(err, "register configmap event handler")
+	}
+
+	factory.Start(ctx.Done())
+	factory.WaitForCacheSync(ctx.Done())
+
+	<-ctx.Done()
+	return ctx.Err()
+}
+
+// HTTPRuntimeConfigSource polls a URL on an interval, using conditional
+// GETs (If-None-Match) so unchanged configs cost little more than a 304.
+type HTTPRuntimeConfigSource struct {
+	url      string
+	interval time.Duration
+	client   *http.Client
+	log      logrus.FieldLogger
+
+	// etagMu guards lastETag: RuntimeConfigManager calls Load and Watch from
+	// separate goroutines (Load once at startup, Watch's polling loop
+	// afterwards), and fetch reads then writes lastETag on every call.
+	etagMu   sync.Mutex
+	lastETag string
+}
+
+// NewHTTPRuntimeConfigSource returns a RuntimeConfigSource that polls url
+// every interval.
+func NewHTTPRuntimeConfigSource(url string, interval time.Duration, client *http.Client, log logrus.FieldLogger) *HTTPRuntimeConfigSource {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	return &HTTPRuntimeConfigSource{url: url, interval: interval, client: client, log: log}
+}
+
+func (s *HTTPRuntimeConfigSource) Name() string {
+	return fmt.Sprintf("http:%s", s.url)
+}
+
+func (s *HTTPRuntimeConfigSource) Load(ctx context.Context) ([]byte, error) {
+	buf, _, err := s.fetch(ctx)
+	return buf, err
+}
+
+// fetch issues the conditional GET, returning (nil, false, nil) when the
+// server replied 304 Not Modified.
+func (s *HTTPRuntimeConfigSource) fetch(ctx context.Context) ([]byte, bool, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.url, nil)
+	if err != nil {
+		return nil, false, errors.Wrap(err, "build request")
+	}
+	s.etagMu.Lock()
+	lastETag := s.lastETag
+	s.etagMu.Unlock()
+	if lastETag != "" {
+		req.Header.Set("If-None-Match", lastETag)
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, false, errors.Wrap(err, "fetch runtime config")
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotModified {
+		return nil, false, nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, false, errors.Errorf("unexpected status %d fetching %s", resp.StatusCode, s.url)
+	}
+
+	buf, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, false, errors.Wrap(err, "read response body")
+	}
+
+	s.etagMu.Lock()
+	s.lastETag = resp.Header.Get("ETag")
+	s.etagMu.Unlock()
+	return buf, true, nil
+}
+
+func (s *HTTPRuntimeConfigSource) Watch(ctx context.Context, onChange func([]byte)) error {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+			buf, changed, err := s.fetch(ctx)
+			if err != nil {
+				s.log.WithError(err).WithField("source", s.Name()).
+					Warn("runtime overrides: failed to poll http source, keeping last known-good snapshot")
+				continue
+			}
+			if changed {
+				onChange(buf)
+			}
+		}
+	}
+}
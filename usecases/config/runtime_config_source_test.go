@@ -0,0 +1,214 @@
+//                           _       _
+// __      _____  __ ___   ___  __ _| |_ ___
+// \ \ /\ / / _ \/ _` \ \ / / |/ _` | __/ _ \
+//  \ V  V /  __/ (_| |\ V /| | (_| | ||  __/
+//   \_/\_/ \___|\__,_| \_/ |_|\__,_|\__\___|
+//
+//  Copyright © 2016 - 2024 Weaviate B.V. All rights reserved.
+//
+//  CONTACT: hello@weaviate.io
+//
+
+package config
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func discardLogger() logrus.FieldLogger {
+	log := logrus.New()
+	log.SetOutput(io.Discard)
+	return log
+}
+
+func TestFileRuntimeConfigSource(t *testing.T) {
+	t.Run("Load returns the file's current contents", func(t *testing.T) {
+		dir := t.TempDir()
+		path := filepath.Join(dir, "runtime-overrides.yaml")
+		require.NoError(t, os.WriteFile(path, []byte(`autoschema_enabled: true`), 0o644))
+
+		s := NewFileRuntimeConfigSource(path, discardLogger())
+		buf, err := s.Load(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, `autoschema_enabled: true`, string(buf))
+		assert.Equal(t, "file:"+path, s.Name())
+	})
+
+	t.Run("Load surfaces an error for a missing file", func(t *testing.T) {
+		s := NewFileRuntimeConfigSource(filepath.Join(t.TempDir(), "missing.yaml"), discardLogger())
+		_, err := s.Load(context.Background())
+		require.Error(t, err)
+	})
+
+	t.Run("Watch notifies on a rename-style replace of the file", func(t *testing.T) {
+		dir := t.TempDir()
+		path := filepath.Join(dir, "runtime-overrides.yaml")
+		require.NoError(t, os.WriteFile(path, []byte(`autoschema_enabled: false`), 0o644))
+
+		s := NewFileRuntimeConfigSource(path, discardLogger())
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		changes := make(chan []byte, 1)
+		go func() {
+			_ = s.Watch(ctx, func(buf []byte) { changes <- buf })
+		}()
+
+		// give the watcher a moment to start watching the parent dir.
+		time.Sleep(100 * time.Millisecond)
+
+		// Simulate an atomic config map / editor replace: write to a temp
+		// file then rename over the watched path.
+		tmp := path + ".tmp"
+		require.NoError(t, os.WriteFile(tmp, []byte(`autoschema_enabled: true`), 0o644))
+		require.NoError(t, os.Rename(tmp, path))
+
+		select {
+		case buf := <-changes:
+			assert.Equal(t, `autoschema_enabled: true`, string(buf))
+		case <-time.After(5 * time.Second):
+			t.Fatal("Watch never observed the rename-based replace")
+		}
+	})
+}
+
+func TestHTTPRuntimeConfigSource(t *testing.T) {
+	t.Run("Load fetches the current body and records its ETag", func(t *testing.T) {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("ETag", `"v1"`)
+			w.Write([]byte(`autoschema_enabled: true`))
+		}))
+		defer srv.Close()
+
+		s := NewHTTPRuntimeConfigSource(srv.URL, time.Second, srv.Client(), discardLogger())
+		buf, err := s.Load(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, `autoschema_enabled: true`, string(buf))
+		assert.Equal(t, "http:"+srv.URL, s.Name())
+	})
+
+	t.Run("a conditional refetch with a matching ETag is reported as unchanged", func(t *testing.T) {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Header.Get("If-None-Match") == `"v1"` {
+				w.WriteHeader(http.StatusNotModified)
+				return
+			}
+			w.Header().Set("ETag", `"v1"`)
+			w.Write([]byte(`autoschema_enabled: true`))
+		}))
+		defer srv.Close()
+
+		s := NewHTTPRuntimeConfigSource(srv.URL, time.Second, srv.Client(), discardLogger())
+
+		_, changed, err := s.fetch(context.Background())
+		require.NoError(t, err)
+		assert.True(t, changed)
+
+		_, changed, err = s.fetch(context.Background())
+		require.NoError(t, err)
+		assert.False(t, changed)
+	})
+
+	t.Run("concurrent Load and fetch calls don't race on lastETag", func(t *testing.T) {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("ETag", `"v1"`)
+			w.Write([]byte(`autoschema_enabled: true`))
+		}))
+		defer srv.Close()
+
+		s := NewHTTPRuntimeConfigSource(srv.URL, time.Second, srv.Client(), discardLogger())
+
+		var wg sync.WaitGroup
+		for i := 0; i < 20; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				_, _, _ = s.fetch(context.Background())
+			}()
+		}
+		wg.Wait()
+	})
+}
+
+func TestConfigMapRuntimeConfigSource(t *testing.T) {
+	t.Run("Load extracts the configured key", func(t *testing.T) {
+		client := fake.NewSimpleClientset(&corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: "runtime-overrides", Namespace: "weaviate"},
+			Data:       map[string]string{"config.yaml": `autoschema_enabled: true`},
+		})
+
+		s := NewConfigMapRuntimeConfigSource(client, "weaviate", "runtime-overrides", "config.yaml", discardLogger())
+		buf, err := s.Load(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, `autoschema_enabled: true`, string(buf))
+		assert.Equal(t, "configmap:weaviate/runtime-overrides", s.Name())
+	})
+
+	t.Run("Load errors if the configured key is missing", func(t *testing.T) {
+		client := fake.NewSimpleClientset(&corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: "runtime-overrides", Namespace: "weaviate"},
+			Data:       map[string]string{},
+		})
+
+		s := NewConfigMapRuntimeConfigSource(client, "weaviate", "runtime-overrides", "config.yaml", discardLogger())
+		_, err := s.Load(context.Background())
+		require.Error(t, err)
+	})
+
+	t.Run("Watch notifies when the ConfigMap is updated", func(t *testing.T) {
+		client := fake.NewSimpleClientset(&corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: "runtime-overrides", Namespace: "weaviate"},
+			Data:       map[string]string{"config.yaml": `autoschema_enabled: false`},
+		})
+
+		s := NewConfigMapRuntimeConfigSource(client, "weaviate", "runtime-overrides", "config.yaml", discardLogger())
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		changes := make(chan []byte, 1)
+		go func() {
+			_ = s.Watch(ctx, func(buf []byte) { changes <- buf })
+		}()
+
+		// The informer's initial list delivers the ConfigMap's current
+		// contents through AddFunc before any update is observed.
+		select {
+		case buf := <-changes:
+			assert.Equal(t, `autoschema_enabled: false`, string(buf))
+		case <-time.After(5 * time.Second):
+			t.Fatal("Watch never delivered the ConfigMap's initial contents")
+		}
+
+		time.Sleep(100 * time.Millisecond)
+
+		_, err := client.CoreV1().ConfigMaps("weaviate").Update(ctx, &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: "runtime-overrides", Namespace: "weaviate"},
+			Data:       map[string]string{"config.yaml": `autoschema_enabled: true`},
+		}, metav1.UpdateOptions{})
+		require.NoError(t, err)
+
+		select {
+		case buf := <-changes:
+			assert.Equal(t, `autoschema_enabled: true`, string(buf))
+		case <-time.After(5 * time.Second):
+			t.Fatal("Watch never observed the ConfigMap update")
+		}
+	})
+}
@@ -0,0 +1,193 @@
+//                           _       _
+// __      _____  __ ___   ___  __ _| |_ ___
+// \ \ /\ / / _ \/ _` \ \ / / |/ _` | __/ _ \
+//  \ V  V /  __/ (_| |\ V /| | (_| | ||  __/
+//   \_/\_/ \___|\__,_| \_/ |_|\__,_|\__\___|
+//
+//  Copyright © 2016 - 2024 Weaviate B.V. All rights reserved.
+//
+//  CONTACT: hello@weaviate.io
+//
+
+package config
+
+import (
+	"bytes"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"gopkg.in/yaml.v3"
+
+	"github.com/weaviate/weaviate/usecases/config/runtime"
+)
+
+// WeaviateRuntimeConfig lists every config value that is allowed to change
+// while the node is running, e.g. via the YAML file referenced by
+// `--runtime-overrides-path`. Each field must be a
+// `*runtime.DynamicValue[T]` so it can be read lock-free from hot paths and
+// safely overwritten by UpdateRuntimeConfig whenever the backing source
+// changes.
+//
+// The `yaml`/`json` tag of every field must be lower_snake_case; this is
+// asserted by TestParseRuntimeConfig so that the on-disk representation
+// stays consistent as fields are added.
+//
+// Fields may additionally carry a `runtimevalidate` tag describing the
+// constraint the parsed value must satisfy before UpdateRuntimeConfig will
+// apply it; see runtimeconfig_schema.go.
+type WeaviateRuntimeConfig struct {
+	MaximumAllowedCollectionsCount  *runtime.DynamicValue[int]           `json:"maximum_allowed_collections_count" yaml:"maximum_allowed_collections_count" runtimevalidate:"min=0,max=1000000"`
+	AutoschemaEnabled               *runtime.DynamicValue[bool]          `json:"autoschema_enabled" yaml:"autoschema_enabled"`
+	AsyncReplicationDisabled        *runtime.DynamicValue[bool]          `json:"async_replication_disabled" yaml:"async_replication_disabled"`
+	TenantActivityReadLogLevel      *runtime.DynamicValue[string]        `json:"tenant_activity_read_log_level" yaml:"tenant_activity_read_log_level" runtimevalidate:"enum=debug|info|warn|error"`
+	TenantActivityWriteLogLevel     *runtime.DynamicValue[string]        `json:"tenant_activity_write_log_level" yaml:"tenant_activity_write_log_level" runtimevalidate:"enum=debug|info|warn|error"`
+	RevectorizeCheckDisabled        *runtime.DynamicValue[bool]          `json:"revectorize_check_disabled" yaml:"revectorize_check_disabled"`
+	ReplicaMovementMinimumAsyncWait *runtime.DynamicValue[time.Duration] `json:"replica_movement_minimum_async_wait" yaml:"replica_movement_minimum_async_wait" runtimevalidate:"min=0s,max=24h"`
+}
+
+// ParseRuntimeConfig parses buf (the contents of a runtime-overrides YAML
+// file) into a WeaviateRuntimeConfig. Unknown fields are treated as a hard
+// error -- typically a typo in the config file -- rather than silently
+// ignored, so operators find out immediately instead of wondering why an
+// override never took effect.
+func ParseRuntimeConfig(buf []byte) (*WeaviateRuntimeConfig, error) {
+	var cfg WeaviateRuntimeConfig
+
+	dec := yaml.NewDecoder(bytes.NewReader(buf))
+	dec.KnownFields(true)
+	if err := dec.Decode(&cfg); err != nil {
+		return nil, errors.Wrap(err, "parse runtime config")
+	}
+
+	return &cfg, nil
+}
+
+// UpdateRuntimeConfigOption customizes a single UpdateRuntimeConfig call.
+type UpdateRuntimeConfigOption func(*updateRuntimeConfigOptions)
+
+type updateRuntimeConfigOptions struct {
+	onChange func(field string, old, new any)
+}
+
+// WithChangeObserver registers a callback invoked once for every field
+// whose effective value actually changes, after validation has passed but
+// before the next UpdateRuntimeConfig call could observe it. Callers that
+// need per-field provenance (e.g. RuntimeConfigManager, recording which
+// source last touched a field) use this instead of re-deriving the diff
+// themselves.
+func WithChangeObserver(onChange func(field string, old, new any)) UpdateRuntimeConfigOption {
+	return func(o *updateRuntimeConfigOptions) {
+		o.onChange = onChange
+	}
+}
+
+// UpdateRuntimeConfig applies every field present in `parsed` onto the
+// corresponding field of `reg`, the live registry of DynamicValue pointers
+// the rest of the codebase reads from. Fields of `reg` that are nil are
+// skipped -- the caller simply hasn't wired that override up -- and fields
+// missing from `parsed` (removed from the config file, or never set) reset
+// the live value back to its zero value rather than leaving a stale
+// override in place.
+//
+// The update is atomic: every candidate value is validated against its
+// runtimevalidate constraint (and, if registered, its DynamicValue's own
+// Validator) before anything is written. If any field fails, the error
+// names every offending field and none of them are applied -- the live
+// config is left exactly as it was.
+//
+// Every effective change is logged in a fixed, greppable format so ops can
+// audit what changed and when without a diff tool.
+//
+// Every call -- regardless of caller -- is counted in the
+// weaviate_runtime_config_reloads_total{result="success|error"} metric.
+func UpdateRuntimeConfig(log logrus.FieldLogger, reg, parsed *WeaviateRuntimeConfig, opts ...UpdateRuntimeConfigOption) (err error) {
+	defer func() { recordRuntimeConfigReload(err) }()
+
+	if reg == nil {
+		return nil
+	}
+
+	var o updateRuntimeConfigOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	regVal := reflect.ValueOf(reg).Elem()
+	regType := regVal.Type()
+
+	var parsedVal reflect.Value
+	if parsed != nil {
+		parsedVal = reflect.ValueOf(parsed).Elem()
+	}
+
+	type pendingUpdate struct {
+		target   reflect.Value
+		field    string
+		oldValue any
+		newValue any
+	}
+	var pending []pendingUpdate
+	var invalid []string
+
+	for i := 0; i < regType.NumField(); i++ {
+		field := regType.Field(i)
+		target := regVal.Field(i)
+		if target.IsNil() {
+			// Nothing in the running process reads this field, nothing to update.
+			continue
+		}
+
+		newVal := zeroDynamicValue(target.Type())
+		if parsedVal.IsValid() {
+			if src := parsedVal.FieldByName(field.Name); src.IsValid() && !src.IsNil() {
+				newVal = src
+			}
+		}
+
+		newValue := newVal.MethodByName("Get").Call(nil)[0]
+
+		if err := validateField(field, newValue.Interface()); err != nil {
+			invalid = append(invalid, fmt.Sprintf("%s: %s", field.Name, err))
+			continue
+		}
+		if errVal := target.MethodByName("Validate").Call([]reflect.Value{newValue})[0]; !errVal.IsNil() {
+			invalid = append(invalid, fmt.Sprintf("%s: %s", field.Name, errVal.Interface().(error)))
+			continue
+		}
+
+		oldValue := target.MethodByName("Get").Call(nil)[0].Interface()
+		pending = append(pending, pendingUpdate{target: target, field: field.Name, oldValue: oldValue, newValue: newValue.Interface()})
+	}
+
+	if len(invalid) > 0 {
+		return errors.Errorf("runtime overrides: rejected invalid config, no changes applied: %s", strings.Join(invalid, "; "))
+	}
+
+	for _, u := range pending {
+		if !reflect.DeepEqual(u.oldValue, u.newValue) {
+			log.WithFields(logrus.Fields{
+				"action":    "runtime_overrides_changed",
+				"field":     u.field,
+				"old_value": u.oldValue,
+				"new_value": u.newValue,
+			}).Infof("runtime overrides: config '%s' changed from '%v' to '%v'", u.field, u.oldValue, u.newValue)
+			if o.onChange != nil {
+				o.onChange(u.field, u.oldValue, u.newValue)
+			}
+		}
+		u.target.MethodByName("SetValue").Call([]reflect.Value{reflect.ValueOf(u.newValue)})
+	}
+
+	return nil
+}
+
+// zeroDynamicValue returns a fresh, unset *runtime.DynamicValue[T] for the
+// given field type, used as the fallback when a field is absent from a
+// parsed config so the live value resets to its zero value.
+func zeroDynamicValue(fieldType reflect.Type) reflect.Value {
+	return reflect.New(fieldType.Elem())
+}
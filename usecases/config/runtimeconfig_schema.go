@@ -0,0 +1,224 @@
+//                           _       _
+// __      _____  __ ___   ___  __ _| |_ ___
+// \ \ /\ / / _ \/ _` \ \ / / |/ _` | __/ _ \
+//  \ V  V /  __/ (_| |\ V /| | (_| | ||  __/
+//   \_/\_/ \___|\__,_| \_/ |_|\__,_|\__\___|
+//
+//  Copyright © 2016 - 2024 Weaviate B.V. All rights reserved.
+//
+//  CONTACT: hello@weaviate.io
+//
+
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// FieldConstraint describes the validation schema of a single
+// WeaviateRuntimeConfig field, derived from its `runtimevalidate` struct
+// tag. It is exported so an admin API can render the schema (name, type,
+// default, constraints) to operators, e.g. via DescribeRuntimeConfig.
+type FieldConstraint struct {
+	Field   string
+	Type    string
+	Default any
+
+	Min  any // int or time.Duration, nil if unbounded
+	Max  any // int or time.Duration, nil if unbounded
+	Enum []string
+	Regex string
+
+	regex *regexp.Regexp // compiled Regex, nil if Regex is empty
+}
+
+// runtimeConfigSchema is built once, at package init, from
+// WeaviateRuntimeConfig's `runtimevalidate` tags. DescribeRuntimeConfig is
+// called from the admin endpoint and from every RuntimeConfigManager.apply,
+// so a malformed tag must fail loudly at process startup -- not panic one
+// of those live paths the first time a typo'd tag is parsed.
+var runtimeConfigSchema = buildRuntimeConfigSchema()
+
+func buildRuntimeConfigSchema() []FieldConstraint {
+	t := reflect.TypeOf(WeaviateRuntimeConfig{})
+
+	out := make([]FieldConstraint, 0, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		fc, err := parseRuntimeValidateTag(field)
+		if err != nil {
+			// A malformed tag is a programmer error: better to fail fast at
+			// startup (and in TestDescribeRuntimeConfig) than to panic the
+			// admin handler or the config-reload path on first use.
+			panic(fmt.Sprintf("runtime config field %q has invalid runtimevalidate tag: %s", field.Name, err))
+		}
+		fc.Default = zeroDynamicValue(field.Type).MethodByName("Get").Call(nil)[0].Interface()
+		out = append(out, fc)
+	}
+	return out
+}
+
+// DescribeRuntimeConfig returns the validation schema for every field of
+// WeaviateRuntimeConfig, in declaration order.
+func DescribeRuntimeConfig() []FieldConstraint {
+	out := make([]FieldConstraint, len(runtimeConfigSchema))
+	copy(out, runtimeConfigSchema)
+	return out
+}
+
+// runtimeConfigSchemaByField indexes runtimeConfigSchema by Field, so
+// validateField can look up an already-built FieldConstraint instead of
+// re-parsing the `runtimevalidate` tag (and recompiling any regex) on every
+// UpdateRuntimeConfig call.
+var runtimeConfigSchemaByField = indexRuntimeConfigSchema()
+
+func indexRuntimeConfigSchema() map[string]FieldConstraint {
+	out := make(map[string]FieldConstraint, len(runtimeConfigSchema))
+	for _, fc := range runtimeConfigSchema {
+		out[fc.Field] = fc
+	}
+	return out
+}
+
+// parseRuntimeValidateTag builds a FieldConstraint for field from its
+// `runtimevalidate` struct tag. Supported keys, comma-separated:
+//
+//	min=<value>,max=<value>   numeric and time.Duration bounds
+//	enum=a|b|c                allowed values for strings
+//	regex=<pattern>           allowed pattern for strings
+//
+// Fields with no tag have no constraints beyond their Go type.
+func parseRuntimeValidateTag(field reflect.StructField) (FieldConstraint, error) {
+	if field.Type.Kind() != reflect.Ptr {
+		return FieldConstraint{Field: field.Name}, fmt.Errorf("field %q is not a *runtime.DynamicValue[T] (got %s)", field.Name, field.Type)
+	}
+
+	fc := FieldConstraint{
+		Field: field.Name,
+		Type:  dynamicValueElemTypeName(field.Type),
+	}
+
+	tag, ok := field.Tag.Lookup("runtimevalidate")
+	if !ok {
+		return fc, nil
+	}
+
+	for _, part := range strings.Split(tag, ",") {
+		kv := strings.SplitN(part, "=", 2)
+		if len(kv) != 2 {
+			return fc, fmt.Errorf("malformed constraint %q", part)
+		}
+		key, val := kv[0], kv[1]
+
+		switch key {
+		case "min", "max":
+			bound, err := parseBound(fc.Type, val)
+			if err != nil {
+				return fc, fmt.Errorf("parse %s bound: %w", key, err)
+			}
+			if key == "min" {
+				fc.Min = bound
+			} else {
+				fc.Max = bound
+			}
+		case "enum":
+			fc.Enum = strings.Split(val, "|")
+		case "regex":
+			re, err := regexp.Compile(val)
+			if err != nil {
+				return fc, fmt.Errorf("invalid regex %q: %w", val, err)
+			}
+			fc.Regex = val
+			fc.regex = re
+		default:
+			return fc, fmt.Errorf("unknown constraint key %q", key)
+		}
+	}
+
+	return fc, nil
+}
+
+// dynamicValueElemTypeName returns the human-readable type name of the T
+// in a *runtime.DynamicValue[T] struct field, e.g. "int" or
+// "time.Duration", by picking it out of the instantiated generic type's
+// name (fieldType.Elem().String() renders as "runtime.DynamicValue[int]").
+func dynamicValueElemTypeName(fieldType reflect.Type) string {
+	name := fieldType.Elem().String()
+	start := strings.IndexByte(name, '[')
+	end := strings.LastIndexByte(name, ']')
+	if start < 0 || end < 0 || end <= start {
+		return name
+	}
+	return name[start+1 : end]
+}
+
+func parseBound(typeName, val string) (any, error) {
+	switch typeName {
+	case "time.Duration":
+		d, err := time.ParseDuration(val)
+		if err != nil {
+			return nil, err
+		}
+		return d, nil
+	default:
+		n, err := strconv.Atoi(val)
+		if err != nil {
+			return nil, err
+		}
+		return n, nil
+	}
+}
+
+// validateField checks value (the candidate Get() result of field) against
+// field's already-built FieldConstraint, if any (see runtimeConfigSchema).
+func validateField(field reflect.StructField, value any) error {
+	if _, ok := field.Tag.Lookup("runtimevalidate"); !ok {
+		return nil
+	}
+	fc, ok := runtimeConfigSchemaByField[field.Name]
+	if !ok {
+		return fmt.Errorf("no schema entry for field %q", field.Name)
+	}
+
+	switch v := value.(type) {
+	case int:
+		if fc.Min != nil && v < fc.Min.(int) {
+			return fmt.Errorf("value %d is below minimum %d", v, fc.Min)
+		}
+		if fc.Max != nil && v > fc.Max.(int) {
+			return fmt.Errorf("value %d exceeds maximum %d", v, fc.Max)
+		}
+	case time.Duration:
+		if fc.Min != nil && v < fc.Min.(time.Duration) {
+			return fmt.Errorf("value %s is below minimum %s", v, fc.Min)
+		}
+		if fc.Max != nil && v > fc.Max.(time.Duration) {
+			return fmt.Errorf("value %s exceeds maximum %s", v, fc.Max)
+		}
+	case string:
+		if len(fc.Enum) > 0 && v != "" {
+			var found bool
+			for _, allowed := range fc.Enum {
+				if v == allowed {
+					found = true
+					break
+				}
+			}
+			if !found {
+				return fmt.Errorf("value %q is not one of %v", v, fc.Enum)
+			}
+		}
+		if fc.regex != nil && v != "" {
+			if !fc.regex.MatchString(v) {
+				return fmt.Errorf("value %q does not match pattern %q", v, fc.Regex)
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,90 @@
+//                           _       _
+// __      _____  __ ___   ___  __ _| |_ ___
+// \ \ /\ / / _ \/ _` \ \ / / |/ _` | __/ _ \
+//  \ V  V /  __/ (_| |\ V /| | (_| | ||  __/
+//   \_/\_/ \___|\__,_| \_/ |_|\__,_|\__\___|
+//
+//  Copyright © 2016 - 2024 Weaviate B.V. All rights reserved.
+//
+//  CONTACT: hello@weaviate.io
+//
+
+package config
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/weaviate/weaviate/usecases/config/runtime"
+)
+
+func TestDescribeRuntimeConfig(t *testing.T) {
+	t.Run("describes every field of WeaviateRuntimeConfig, in declaration order", func(t *testing.T) {
+		fcs := DescribeRuntimeConfig()
+
+		wantFields := reflect.TypeOf(WeaviateRuntimeConfig{})
+		require.Len(t, fcs, wantFields.NumField())
+		for i, fc := range fcs {
+			assert.Equal(t, wantFields.Field(i).Name, fc.Field)
+		}
+	})
+
+	t.Run("constraint-tagged fields carry their min/max/enum", func(t *testing.T) {
+		byField := map[string]FieldConstraint{}
+		for _, fc := range DescribeRuntimeConfig() {
+			byField[fc.Field] = fc
+		}
+
+		colCount, ok := byField["MaximumAllowedCollectionsCount"]
+		require.True(t, ok)
+		assert.Equal(t, 0, colCount.Min)
+		assert.Equal(t, 1000000, colCount.Max)
+
+		logLevel, ok := byField["TenantActivityReadLogLevel"]
+		require.True(t, ok)
+		assert.Equal(t, []string{"debug", "info", "warn", "error"}, logLevel.Enum)
+
+		minWait, ok := byField["ReplicaMovementMinimumAsyncWait"]
+		require.True(t, ok)
+		assert.Equal(t, time.Duration(0), minWait.Min)
+		assert.Equal(t, 24*time.Hour, minWait.Max)
+	})
+
+	t.Run("does not mutate the cached schema between calls", func(t *testing.T) {
+		first := DescribeRuntimeConfig()
+		first[0].Field = "tampered"
+
+		second := DescribeRuntimeConfig()
+		assert.NotEqual(t, "tampered", second[0].Field)
+	})
+
+	t.Run("a malformed runtimevalidate tag on a DynamicValue field returns an error, not a panic", func(t *testing.T) {
+		type badConfig struct {
+			Bad *runtime.DynamicValue[int] `runtimevalidate:"min=not-a-number"`
+		}
+		// buildRuntimeConfigSchema operates on the package-level
+		// WeaviateRuntimeConfig; here we just assert that the same tag
+		// parser backing it rejects a malformed tag, which is what
+		// buildRuntimeConfigSchema panics on at package init.
+		var err error
+		require.NotPanics(t, func() {
+			_, err = parseRuntimeValidateTag(reflect.TypeOf(badConfig{}).Field(0))
+		})
+		require.Error(t, err)
+	})
+
+	t.Run("a non-pointer field returns an error instead of panicking on Elem()", func(t *testing.T) {
+		type badConfig struct {
+			Bad int `runtimevalidate:"min=0"`
+		}
+		var err error
+		require.NotPanics(t, func() {
+			_, err = parseRuntimeValidateTag(reflect.TypeOf(badConfig{}).Field(0))
+		})
+		require.Error(t, err)
+	})
+}